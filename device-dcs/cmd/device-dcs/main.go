@@ -9,8 +9,15 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+
 	"github.com/edgexfoundry/device-dcs/driver"
+	internalgrpc "github.com/edgexfoundry/device-dcs/internal/grpc"
+	"github.com/edgexfoundry/device-dcs/pkg/models"
 	"github.com/edgexfoundry/device-dcs/pkg/startup"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/logging"
 )
 
 const (
@@ -19,6 +26,49 @@ const (
 )
 
 func main() {
-	sd := driver.SimpleDriver{}
-	startup.Bootstrap(serviceName, version, &sd)
+	driverName := flag.String("driver", "simple", "ProtocolDriver to run: simple, modbus, evdev, or camera")
+	grpcListen := flag.String("grpc-listen", "", "address to serve the DMI-style management gRPC API on, e.g. :50051 (disabled if empty)")
+	grpcSecure := flag.Bool("grpc-secure", false, "serve the management gRPC API over TLS")
+	grpcCertFile := flag.String("grpc-cert-file", "", "TLS certificate file for the management gRPC API")
+	grpcKeyFile := flag.String("grpc-key-file", "", "TLS key file for the management gRPC API")
+	grpcCAFile := flag.String("grpc-ca-file", "", "CA file used to verify management gRPC API clients (enables mutual TLS)")
+	grpcSkipVerify := flag.Bool("grpc-skip-verify", false, "request but don't verify client certificates for the management gRPC API (requires -grpc-ca-file)")
+	flag.Parse()
+
+	var pd models.ProtocolDriver
+	sd := &driver.SimpleDriver{}
+	switch *driverName {
+	case "modbus":
+		pd = &driver.ModbusDriver{}
+	case "evdev":
+		pd = &driver.EvdevDriver{}
+	case "camera":
+		pd = &driver.CameraDriver{}
+	default:
+		pd = sd
+	}
+
+	if *grpcListen != "" {
+		if *driverName != "simple" {
+			fmt.Fprintln(os.Stderr, "device-dcs: --grpc-listen requires --driver=simple")
+			os.Exit(1)
+		}
+
+		opts := driver.GrpcOptions{
+			Listen:     *grpcListen,
+			Secure:     *grpcSecure,
+			CertFile:   *grpcCertFile,
+			KeyFile:    *grpcKeyFile,
+			CAFile:     *grpcCAFile,
+			SkipVerify: *grpcSkipVerify,
+		}
+		lc := logger.NewClient(serviceName, false, "", "INFO")
+		go func() {
+			if err := internalgrpc.Serve(lc, sd, opts); err != nil {
+				lc.Error(err.Error())
+			}
+		}()
+	}
+
+	startup.Bootstrap(serviceName, version, pd)
 }
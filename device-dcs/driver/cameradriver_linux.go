@@ -0,0 +1,568 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+// This package provides a V4L2 implementation of a ProtocolDriver
+// interface for USB cameras, following the kubeedge usbcamera-dmi
+// mapper's capture approach.
+//
+package driver
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"sync"
+	"time"
+	"unsafe"
+
+	dsModels "github.com/edgexfoundry/device-dcs/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/logging"
+	"github.com/edgexfoundry/go-mod-core-contracts/models"
+	"golang.org/x/sys/unix"
+)
+
+// V4L2 ioctl request codes (from linux/videodev2.h).
+const (
+	vidiocQueryCap  = 0x80685600
+	vidiocSFmt      = 0xc0d05605
+	vidiocReqBufs   = 0xc0145608
+	vidiocQueryBuf  = 0xc0585609
+	vidiocQBuf      = 0xc058560f
+	vidiocDQBuf     = 0xc0585611
+	vidiocStreamOn  = 0x40045612
+	vidiocStreamOff = 0x40045613
+)
+
+const (
+	v4l2BufTypeVideoCapture = 1
+	v4l2MemoryMmap          = 1
+	v4l2PixFmtMJPEG         = 0x47504a4d // 'MJPG'
+	v4l2PixFmtYUYV          = 0x56595559 // 'YUYV'
+)
+
+// v4l2Format mirrors struct v4l2_format. VIDIOC_S_FMT is encoded as
+// _IOWR('V', 5, struct v4l2_format), which is 208 bytes on 64-bit Linux;
+// video_usercopy copies that full size into and back out of the pointer
+// we pass, so the Go struct must be padded out to the same size even
+// though this driver only reads/writes the leading v4l2_pix_format
+// fields of the embedded "fmt" union.
+type v4l2Format struct {
+	Type uint32
+	_    [4]byte // compiler padding before the 8-byte-aligned "fmt" union
+
+	Width        uint32
+	Height       uint32
+	PixelFormat  uint32
+	Field        uint32
+	BytesPerLine uint32
+	SizeImage    uint32
+	Colorspace   uint32
+
+	_ [172]byte // remainder of the 200-byte "fmt" union, unused here
+}
+
+// v4l2RequestBuffers mirrors struct v4l2_requestbuffers (20 bytes, per
+// VIDIOC_REQBUFS's _IOC size).
+type v4l2RequestBuffers struct {
+	Count        uint32
+	Type         uint32
+	Memory       uint32
+	Capabilities uint32
+	_            [4]byte // reserved
+}
+
+// v4l2Timeval mirrors struct timeval as used in struct v4l2_buffer on
+// 64-bit Linux, where both fields are longs.
+type v4l2Timeval struct {
+	Sec  int64
+	Usec int64
+}
+
+// v4l2Timecode mirrors struct v4l2_timecode.
+type v4l2Timecode struct {
+	Type     uint32
+	Flags    uint32
+	Frames   uint8
+	Seconds  uint8
+	Minutes  uint8
+	Hours    uint8
+	Userbits [4]uint8
+}
+
+// v4l2Buffer mirrors struct v4l2_buffer. VIDIOC_QUERYBUF/VIDIOC_QBUF/
+// VIDIOC_DQBUF are all encoded with an 88-byte struct v4l2_buffer (on
+// 64-bit Linux); the kernel copies that full size both ways, so every
+// field up to the trailing union must be accounted for even though this
+// driver only reads/writes Index/Type/BytesUsed/Flags/Field/Memory/
+// Offset/Length.
+type v4l2Buffer struct {
+	Index     uint32
+	Type      uint32
+	BytesUsed uint32
+	Flags     uint32
+	Field     uint32
+	_         [4]byte // compiler padding before the 8-byte-aligned Timestamp
+	Timestamp v4l2Timeval
+	Timecode  v4l2Timecode
+	Sequence  uint32
+	Memory    uint32
+	Offset    uint32   // first member of the kernel's offset/userptr/planes/fd union
+	_         [4]byte  // remainder of that 8-byte union slot
+	Length    uint32
+	Reserved2 uint32
+	RequestFD int32
+	_         [4]byte // pad the struct out to the kernel's 88-byte size
+}
+
+// mmapBuffer is one mmap'd capture buffer and its device-side metadata.
+type mmapBuffer struct {
+	data []byte
+}
+
+// cameraSession tracks everything needed to capture frames from an open
+// /dev/videoN node and tear it down deterministically.
+type cameraSession struct {
+	file       *os.File
+	fd         int
+	width      uint32
+	height     uint32
+	pixFmt     uint32
+	buffers    []mmapBuffer
+	cancelPipe [2]int
+	stop       chan struct{}
+	done       chan struct{}
+	streaming  bool
+}
+
+// CameraDriver captures still frames, and optionally streams them, from
+// V4L2 USB cameras.
+type CameraDriver struct {
+	lc      logger.LoggingClient
+	asyncCh chan<- *dsModels.AsyncValues
+
+	mu       sync.Mutex
+	sessions map[string]*cameraSession
+}
+
+// Initialize performs protocol-specific initialization for the device
+// service.
+func (d *CameraDriver) Initialize(lc logger.LoggingClient, asyncCh chan<- *dsModels.AsyncValues) error {
+	d.lc = lc
+	d.asyncCh = asyncCh
+	d.sessions = make(map[string]*cameraSession)
+	return nil
+}
+
+// HandleReadCommands triggers a protocol Read operation for the specified device.
+// A request for the "Snapshot" resource captures and returns a single JPEG
+// frame; any other resource is rejected.
+func (d *CameraDriver) HandleReadCommands(deviceName string, protocols map[string]models.ProtocolProperties, reqs []dsModels.CommandRequest) (res []*dsModels.CommandValue, err error) {
+	res = make([]*dsModels.CommandValue, len(reqs))
+	for i, req := range reqs {
+		if req.DeviceResource.Name != "Snapshot" {
+			return nil, fmt.Errorf("CameraDriver.HandleReadCommands: unsupported resource %s", req.DeviceResource.Name)
+		}
+
+		session, err := d.sessionFor(deviceName, protocols)
+		if err != nil {
+			return nil, fmt.Errorf("CameraDriver.HandleReadCommands: %v", err)
+		}
+
+		jpegBytes, err := captureFrame(session)
+		if err != nil {
+			return nil, fmt.Errorf("CameraDriver.HandleReadCommands: %v", err)
+		}
+
+		now := time.Now().UnixNano() / int64(time.Millisecond)
+		cv, err := dsModels.NewBinaryValue(&reqs[i].RO, now, jpegBytes)
+		if err != nil {
+			return nil, fmt.Errorf("CameraDriver.HandleReadCommands: %v", err)
+		}
+		cv.MediaType = "image/jpeg"
+		res[i] = cv
+	}
+
+	return res, nil
+}
+
+// HandleWriteCommands passes a slice of CommandRequest struct each representing
+// a ResourceOperation for a specific device resource. Writing true to the
+// "Stream" resource starts pushing frames on asyncCh via StartStreaming;
+// writing false tears the session down, stopping it.
+func (d *CameraDriver) HandleWriteCommands(deviceName string, protocols map[string]models.ProtocolProperties, reqs []dsModels.CommandRequest,
+	params []*dsModels.CommandValue) error {
+	for i, req := range reqs {
+		if req.DeviceResource.Name != "Stream" {
+			return fmt.Errorf("CameraDriver.HandleWriteCommands: unsupported resource %s", req.DeviceResource.Name)
+		}
+
+		start, err := params[i].BoolValue()
+		if err != nil {
+			return fmt.Errorf("CameraDriver.HandleWriteCommands: %v", err)
+		}
+
+		if start {
+			if err := d.StartStreaming(deviceName, protocols); err != nil {
+				return fmt.Errorf("CameraDriver.HandleWriteCommands: %v", err)
+			}
+		} else {
+			d.closeSession(deviceName)
+		}
+	}
+
+	return nil
+}
+
+// DisconnectDevice handles protocol-specific cleanup when a device
+// is removed.
+func (d *CameraDriver) DisconnectDevice(deviceName string, protocols map[string]models.ProtocolProperties) error {
+	d.closeSession(deviceName)
+	return nil
+}
+
+// Stop the protocol-specific DS code to shutdown gracefully, or
+// if the force parameter is 'true', immediately.
+func (d *CameraDriver) Stop(force bool) error {
+	d.lc.Debug(fmt.Sprintf("CameraDriver.Stop called: force=%v", force))
+
+	d.mu.Lock()
+	names := make([]string, 0, len(d.sessions))
+	for name := range d.sessions {
+		names = append(names, name)
+	}
+	d.mu.Unlock()
+
+	for _, name := range names {
+		d.closeSession(name)
+	}
+
+	return nil
+}
+
+// StartStreaming begins pushing frames on asyncCh at the configured
+// FrameRate for resource "Snapshot", until Stop or DisconnectDevice
+// writes to the session's cancel pipe.
+func (d *CameraDriver) StartStreaming(deviceName string, protocols map[string]models.ProtocolProperties) error {
+	session, err := d.sessionFor(deviceName, protocols)
+	if err != nil {
+		return err
+	}
+
+	if err := startCapture(session); err != nil {
+		return err
+	}
+
+	props := protocols["v4l2"]
+	frameRate, err := parsePositiveInt(props["FrameRate"], 15)
+	if err != nil {
+		return err
+	}
+
+	go d.streamLoop(deviceName, session, frameRate)
+	return nil
+}
+
+func (d *CameraDriver) streamLoop(deviceName string, session *cameraSession, frameRate int) {
+	defer close(session.done)
+
+	interval := time.Second / time.Duration(frameRate)
+	fds := []unix.PollFd{{Fd: int32(session.cancelPipe[0]), Events: unix.POLLIN}}
+
+	for {
+		// Ppoll blocks until the cancel pipe becomes readable or interval
+		// elapses, whichever is first, so a slow/stuck consumer never
+		// starves the cancel check and an idle session never busy-loops.
+		timeout := unix.NsecToTimespec(interval.Nanoseconds())
+		n, err := unix.Ppoll(fds, &timeout, nil)
+		if err != nil && err != unix.EINTR {
+			d.lc.Warn(fmt.Sprintf("CameraDriver: ppoll error on %s: %v", deviceName, err))
+			return
+		}
+		if n > 0 {
+			// The only fd polled is the cancel pipe; it fired.
+			return
+		}
+
+		raw, err := dequeueFrame(session)
+		if err != nil {
+			d.lc.Warn(fmt.Sprintf("CameraDriver: dequeue error on %s: %v", deviceName, err))
+			continue
+		}
+
+		jpegBytes, err := toJPEG(raw, session.width, session.height, session.pixFmt)
+		if err != nil {
+			d.lc.Warn(fmt.Sprintf("CameraDriver: transcode error on %s: %v", deviceName, err))
+			continue
+		}
+
+		now := time.Now().UnixNano() / int64(time.Millisecond)
+		ro := &dsModels.ResourceOperation{Object: "Snapshot"}
+		cv, _ := dsModels.NewBinaryValue(ro, now, jpegBytes)
+		cv.MediaType = "image/jpeg"
+
+		select {
+		case d.asyncCh <- &dsModels.AsyncValues{DeviceName: deviceName, CommandValues: []*dsModels.CommandValue{cv}}:
+		case <-session.stop:
+			return
+		}
+	}
+}
+
+// sessionFor returns the session for deviceName, opening and configuring
+// the V4L2 node on first use.
+func (d *CameraDriver) sessionFor(deviceName string, protocols map[string]models.ProtocolProperties) (*cameraSession, error) {
+	d.mu.Lock()
+	if session, ok := d.sessions[deviceName]; ok {
+		d.mu.Unlock()
+		return session, nil
+	}
+	d.mu.Unlock()
+
+	session, err := openCamera(protocols)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	d.sessions[deviceName] = session
+	d.mu.Unlock()
+
+	return session, nil
+}
+
+func (d *CameraDriver) closeSession(deviceName string) {
+	d.mu.Lock()
+	session, ok := d.sessions[deviceName]
+	if ok {
+		delete(d.sessions, deviceName)
+	}
+	d.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	if session.streaming {
+		close(session.stop)
+		unix.Write(session.cancelPipe[1], []byte{0})
+		<-session.done
+		ioctl(session.fd, vidiocStreamOff, uintptr(unsafe.Pointer(&[]uint32{v4l2BufTypeVideoCapture}[0])))
+	}
+
+	for _, buf := range session.buffers {
+		unix.Munmap(buf.data)
+	}
+	unix.Close(session.cancelPipe[0])
+	unix.Close(session.cancelPipe[1])
+	session.file.Close()
+}
+
+// openCamera opens DevNode non-blocking (so a later Stop is never at the
+// mercy of a blocking Read/ioctl, the same rationale as EvdevDriver),
+// negotiates the configured format, and maps capture buffers.
+func openCamera(protocols map[string]models.ProtocolProperties) (*cameraSession, error) {
+	props, ok := protocols["v4l2"]
+	if !ok {
+		return nil, fmt.Errorf("no v4l2 protocol properties found")
+	}
+	devNode := props["DevNode"]
+
+	f, err := os.OpenFile(devNode, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", devNode, err)
+	}
+
+	rawConn, err := f.SyscallConn()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to get raw conn for %s: %v", devNode, err)
+	}
+
+	var fd int
+	var ctlErr error
+	err = rawConn.Control(func(sysfd uintptr) {
+		fd = int(sysfd)
+		ctlErr = unix.SetNonblock(fd, true)
+	})
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to control %s: %v", devNode, err)
+	}
+	if ctlErr != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to set %s nonblocking: %v", devNode, ctlErr)
+	}
+
+	width, err := parsePositiveInt(props["Width"], 640)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	height, err := parsePositiveInt(props["Height"], 480)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	pixFmt := v4l2PixFmtYUYV
+	if props["PixelFormat"] == "MJPEG" {
+		pixFmt = v4l2PixFmtMJPEG
+	}
+
+	format := v4l2Format{Type: v4l2BufTypeVideoCapture, Width: uint32(width), Height: uint32(height), PixelFormat: uint32(pixFmt)}
+	if err := ioctl(fd, vidiocSFmt, uintptr(unsafe.Pointer(&format))); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("VIDIOC_S_FMT failed on %s: %v", devNode, err)
+	}
+
+	reqBufs := v4l2RequestBuffers{Count: 4, Type: v4l2BufTypeVideoCapture, Memory: v4l2MemoryMmap}
+	if err := ioctl(fd, vidiocReqBufs, uintptr(unsafe.Pointer(&reqBufs))); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("VIDIOC_REQBUFS failed on %s: %v", devNode, err)
+	}
+
+	buffers := make([]mmapBuffer, 0, reqBufs.Count)
+	for i := uint32(0); i < reqBufs.Count; i++ {
+		buf := v4l2Buffer{Index: i, Type: v4l2BufTypeVideoCapture, Memory: v4l2MemoryMmap}
+		if err := ioctl(fd, vidiocQueryBuf, uintptr(unsafe.Pointer(&buf))); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("VIDIOC_QUERYBUF failed on %s: %v", devNode, err)
+		}
+
+		data, err := unix.Mmap(fd, int64(buf.Offset), int(buf.Length), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("mmap failed on %s: %v", devNode, err)
+		}
+		buffers = append(buffers, mmapBuffer{data: data})
+
+		if err := ioctl(fd, vidiocQBuf, uintptr(unsafe.Pointer(&buf))); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("VIDIOC_QBUF failed on %s: %v", devNode, err)
+		}
+	}
+
+	cancelPipe := [2]int{}
+	if err := unix.Pipe2(cancelPipe[:], unix.O_NONBLOCK); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to create cancel pipe for %s: %v", devNode, err)
+	}
+
+	return &cameraSession{
+		file:       f,
+		fd:         fd,
+		width:      uint32(width),
+		height:     uint32(height),
+		pixFmt:     uint32(pixFmt),
+		buffers:    buffers,
+		cancelPipe: cancelPipe,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}, nil
+}
+
+func startCapture(session *cameraSession) error {
+	if session.streaming {
+		return nil
+	}
+	bufType := uint32(v4l2BufTypeVideoCapture)
+	if err := ioctl(session.fd, vidiocStreamOn, uintptr(unsafe.Pointer(&bufType))); err != nil {
+		return fmt.Errorf("VIDIOC_STREAMON failed: %v", err)
+	}
+	session.streaming = true
+	return nil
+}
+
+// captureFrame starts the stream if needed, dequeues exactly one frame,
+// transcodes it, and leaves the stream running for the next capture.
+func captureFrame(session *cameraSession) ([]byte, error) {
+	if err := startCapture(session); err != nil {
+		return nil, err
+	}
+
+	raw, err := dequeueFrame(session)
+	if err != nil {
+		return nil, err
+	}
+
+	return toJPEG(raw, session.width, session.height, session.pixFmt)
+}
+
+// dequeueFrame polls the capture fd, dequeues one buffer, copies its
+// bytes out, and re-queues the buffer for the driver to refill.
+func dequeueFrame(session *cameraSession) ([]byte, error) {
+	fds := []unix.PollFd{{Fd: int32(session.fd), Events: unix.POLLIN}}
+	if _, err := unix.Ppoll(fds, nil, nil); err != nil {
+		return nil, fmt.Errorf("ppoll failed: %v", err)
+	}
+
+	buf := v4l2Buffer{Type: v4l2BufTypeVideoCapture, Memory: v4l2MemoryMmap}
+	if err := ioctl(session.fd, vidiocDQBuf, uintptr(unsafe.Pointer(&buf))); err != nil {
+		return nil, fmt.Errorf("VIDIOC_DQBUF failed: %v", err)
+	}
+
+	raw := make([]byte, buf.BytesUsed)
+	copy(raw, session.buffers[buf.Index].data[:buf.BytesUsed])
+
+	if err := ioctl(session.fd, vidiocQBuf, uintptr(unsafe.Pointer(&buf))); err != nil {
+		return nil, fmt.Errorf("VIDIOC_QBUF failed: %v", err)
+	}
+
+	return raw, nil
+}
+
+// toJPEG returns raw unchanged when it is already MJPEG, otherwise
+// transcodes a YUYV frame to JPEG.
+func toJPEG(raw []byte, width, height, pixFmt uint32) ([]byte, error) {
+	if pixFmt == v4l2PixFmtMJPEG {
+		return raw, nil
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, int(width), int(height)))
+	for i := 0; i+3 < len(raw) && (i/4)*2+1 < int(width*height); i += 4 {
+		y0, u, y1, v := raw[i], raw[i+1], raw[i+2], raw[i+3]
+		px := (i / 4) * 2
+		x0, y0row := px%int(width), px/int(width)
+		x1 := x0 + 1
+
+		c0 := color.YCbCr{Y: y0, Cb: u, Cr: v}
+		c1 := color.YCbCr{Y: y1, Cb: u, Cr: v}
+		img.Set(x0, y0row, c0)
+		if x1 < int(width) {
+			img.Set(x1, y0row, c1)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, fmt.Errorf("jpeg encode failed: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func parsePositiveInt(s string, def int) (int, error) {
+	if s == "" {
+		return def, nil
+	}
+	var v int
+	if _, err := fmt.Sscanf(s, "%d", &v); err != nil || v <= 0 {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+func ioctl(fd int, req uint, arg uintptr) error {
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), uintptr(req), arg)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+// This file lets the example compile on non-Linux platforms; V4L2 is a
+// Linux-only API, so CameraDriver is not functional here.
+//
+package driver
+
+import (
+	"fmt"
+
+	dsModels "github.com/edgexfoundry/device-dcs/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/logging"
+	"github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+// CameraDriver is a non-functional stub outside of Linux, where V4L2 is
+// unavailable.
+type CameraDriver struct {
+	lc logger.LoggingClient
+}
+
+func (d *CameraDriver) Initialize(lc logger.LoggingClient, asyncCh chan<- *dsModels.AsyncValues) error {
+	d.lc = lc
+	return nil
+}
+
+func (d *CameraDriver) HandleReadCommands(deviceName string, protocols map[string]models.ProtocolProperties, reqs []dsModels.CommandRequest) (res []*dsModels.CommandValue, err error) {
+	return nil, fmt.Errorf("CameraDriver: V4L2 is only supported on Linux")
+}
+
+func (d *CameraDriver) HandleWriteCommands(deviceName string, protocols map[string]models.ProtocolProperties, reqs []dsModels.CommandRequest,
+	params []*dsModels.CommandValue) error {
+	return fmt.Errorf("CameraDriver: V4L2 is only supported on Linux")
+}
+
+func (d *CameraDriver) DisconnectDevice(deviceName string, protocols map[string]models.ProtocolProperties) error {
+	return nil
+}
+
+func (d *CameraDriver) Stop(force bool) error {
+	return nil
+}
@@ -0,0 +1,116 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This file loads SimpleDriver's [Discover] and [[AutoEvents]] sections
+// from the service's configuration.toml, the same file device-sdk-go
+// parses its own [Service]/[Device] sections from.
+//
+package driver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/logging"
+)
+
+// defaultConfDir and defaultProfile mirror device-sdk-go's own
+// "-confdir"/"-cp" and "-profile"/"-p" flag defaults. This package has
+// no access to the flag.FlagSet device-sdk-go parses those from, so it
+// falls back to the EDGEX_CONF_DIR/EDGEX_PROFILE environment variables
+// device-sdk-go also honors for the same settings, rather than
+// hard-coding a path that silently drifts from whatever the SDK was
+// actually started with.
+const (
+	defaultConfDir = "res"
+	defaultProfile = ""
+)
+
+// configFilePath resolves the service's configuration.toml the same
+// way device-sdk-go resolves its own: <confdir>/<profile>/configuration.toml,
+// confdir and profile coming from EDGEX_CONF_DIR/EDGEX_PROFILE when set.
+func configFilePath() string {
+	confDir := os.Getenv("EDGEX_CONF_DIR")
+	if confDir == "" {
+		confDir = defaultConfDir
+	}
+	profile := os.Getenv("EDGEX_PROFILE")
+	if profile == "" {
+		profile = defaultProfile
+	}
+	return filepath.Join(confDir, profile, "configuration.toml")
+}
+
+// driverTOML mirrors the [Discover] and [[AutoEvents]] sections of
+// configuration.toml. Durations are strings (e.g. "30s") since TOML has
+// no native duration type.
+type driverTOML struct {
+	Discover struct {
+		Enable       bool
+		Interval     string
+		DeviceCount  int
+		NameTemplate string
+	}
+	AutoEvents []struct {
+		Resource string
+		Interval string
+		OnChange bool
+	}
+}
+
+// loadDriverConfig reads path's [Discover] and [[AutoEvents]] sections,
+// falling back to DefaultDiscoverConfig/DefaultAutoEventConfigs for
+// whichever section is absent. A missing configuration.toml is not an
+// error: the example still runs against its built-in defaults, but lc
+// logs a warning so an operator who set [Discover]/[[AutoEvents]] and
+// sees them ignored has a signal why.
+func loadDriverConfig(lc logger.LoggingClient, path string) (DiscoverConfig, []AutoEventConfig, error) {
+	discoverCfg := DefaultDiscoverConfig()
+	autoEventCfgs := DefaultAutoEventConfigs()
+
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		lc.Warn(fmt.Sprintf("driver: %s not found, using default [Discover]/[[AutoEvents]] settings", path))
+		return discoverCfg, autoEventCfgs, nil
+	}
+	if err != nil {
+		return discoverCfg, autoEventCfgs, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	var parsed driverTOML
+	if _, err := toml.Decode(string(raw), &parsed); err != nil {
+		return discoverCfg, autoEventCfgs, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	if parsed.Discover.Interval != "" || parsed.Discover.DeviceCount != 0 || parsed.Discover.NameTemplate != "" || parsed.Discover.Enable {
+		discoverCfg.Enable = parsed.Discover.Enable
+		discoverCfg.DeviceCount = parsed.Discover.DeviceCount
+		discoverCfg.NameTemplate = parsed.Discover.NameTemplate
+		if parsed.Discover.Interval != "" {
+			interval, err := time.ParseDuration(parsed.Discover.Interval)
+			if err != nil {
+				return discoverCfg, autoEventCfgs, fmt.Errorf("invalid [Discover] Interval %q: %v", parsed.Discover.Interval, err)
+			}
+			discoverCfg.Interval = interval
+		}
+	}
+
+	if len(parsed.AutoEvents) > 0 {
+		autoEventCfgs = make([]AutoEventConfig, len(parsed.AutoEvents))
+		for i, e := range parsed.AutoEvents {
+			interval, err := time.ParseDuration(e.Interval)
+			if err != nil {
+				return discoverCfg, autoEventCfgs, fmt.Errorf("invalid [[AutoEvents]] Interval %q for resource %s: %v", e.Interval, e.Resource, err)
+			}
+			autoEventCfgs[i] = AutoEventConfig{Resource: e.Resource, Interval: interval, OnChange: e.OnChange}
+		}
+	}
+
+	return discoverCfg, autoEventCfgs, nil
+}
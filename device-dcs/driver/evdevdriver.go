@@ -0,0 +1,320 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+// This package provides an evdev implementation of a ProtocolDriver
+// interface that streams Linux input events (keyboards, joysticks,
+// touch panels) to EdgeX as async readings.
+//
+package driver
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sync"
+
+	dsModels "github.com/edgexfoundry/device-dcs/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/logging"
+	"github.com/edgexfoundry/go-mod-core-contracts/models"
+	"golang.org/x/sys/unix"
+)
+
+// Linux input event types this driver translates into CommandValues.
+const (
+	evKey = 0x01
+	evRel = 0x02
+	evAbs = 0x03
+)
+
+// evdevSession tracks the per-device state needed to read and tear
+// down an open /dev/input/eventN node.
+type evdevSession struct {
+	file       *os.File
+	fd         int
+	cancelPipe [2]int
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// EvdevDriver opens Linux evdev nodes and pushes key/abs/rel readings
+// down asyncCh as they arrive.
+type EvdevDriver struct {
+	lc      logger.LoggingClient
+	asyncCh chan<- *dsModels.AsyncValues
+
+	mu       sync.Mutex
+	sessions map[string]*evdevSession
+}
+
+// Initialize performs protocol-specific initialization for the device
+// service.
+func (d *EvdevDriver) Initialize(lc logger.LoggingClient, asyncCh chan<- *dsModels.AsyncValues) error {
+	d.lc = lc
+	d.asyncCh = asyncCh
+	d.sessions = make(map[string]*evdevSession)
+	return nil
+}
+
+// HandleReadCommands triggers a protocol Read operation for the specified device.
+// EvdevDriver is push-only; readings are delivered asynchronously as they
+// occur on the input node, so synchronous reads always fail. The first
+// call for a device is also what lazily opens its /dev/input/eventN node,
+// the same way SimpleDriver/ModbusDriver lazily create their per-device
+// state on first use.
+func (d *EvdevDriver) HandleReadCommands(deviceName string, protocols map[string]models.ProtocolProperties, reqs []dsModels.CommandRequest) (res []*dsModels.CommandValue, err error) {
+	resources := make([]models.DeviceResource, len(reqs))
+	for i, req := range reqs {
+		resources[i] = req.DeviceResource
+	}
+	if err := d.ensureSession(deviceName, protocols, resources); err != nil {
+		return nil, fmt.Errorf("EvdevDriver.HandleReadCommands: %v", err)
+	}
+
+	return nil, fmt.Errorf("EvdevDriver.HandleReadCommands: device %s is push-only, synchronous reads are not supported", deviceName)
+}
+
+// HandleWriteCommands passes a slice of CommandRequest struct each representing
+// a ResourceOperation for a specific device resource. EvdevDriver exposes no
+// writable resources.
+func (d *EvdevDriver) HandleWriteCommands(deviceName string, protocols map[string]models.ProtocolProperties, reqs []dsModels.CommandRequest,
+	params []*dsModels.CommandValue) error {
+	return fmt.Errorf("EvdevDriver.HandleWriteCommands: device %s has no writable resources", deviceName)
+}
+
+// DisconnectDevice handles protocol-specific cleanup when a device
+// is removed.
+func (d *EvdevDriver) DisconnectDevice(deviceName string, protocols map[string]models.ProtocolProperties) error {
+	d.stopSession(deviceName)
+	return nil
+}
+
+// Stop the protocol-specific DS code to shutdown gracefully, or
+// if the force parameter is 'true', immediately.
+func (d *EvdevDriver) Stop(force bool) error {
+	d.lc.Debug(fmt.Sprintf("EvdevDriver.Stop called: force=%v", force))
+
+	d.mu.Lock()
+	names := make([]string, 0, len(d.sessions))
+	for name := range d.sessions {
+		names = append(names, name)
+	}
+	d.mu.Unlock()
+
+	for _, name := range names {
+		d.stopSession(name)
+	}
+
+	return nil
+}
+
+// ensureSession starts deviceName's poll loop on first use; later calls
+// for the same device are no-ops.
+func (d *EvdevDriver) ensureSession(deviceName string, protocols map[string]models.ProtocolProperties, resources []models.DeviceResource) error {
+	d.mu.Lock()
+	_, ok := d.sessions[deviceName]
+	d.mu.Unlock()
+	if ok {
+		return nil
+	}
+
+	return d.startSession(deviceName, protocols, resources)
+}
+
+// startSession opens the device node and launches its poll loop.
+func (d *EvdevDriver) startSession(deviceName string, protocols map[string]models.ProtocolProperties, resources []models.DeviceResource) error {
+	props, ok := protocols["evdev"]
+	if !ok {
+		return fmt.Errorf("no evdev protocol properties found for device %s", deviceName)
+	}
+	devNode := props["DevNode"]
+
+	f, err := os.OpenFile(devNode, os.O_RDONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", devNode, err)
+	}
+
+	rawConn, err := f.SyscallConn()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to get raw conn for %s: %v", devNode, err)
+	}
+
+	var fd int
+	var ctlErr error
+	err = rawConn.Control(func(sysfd uintptr) {
+		fd = int(sysfd)
+		ctlErr = unix.SetNonblock(fd, true)
+	})
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to control %s: %v", devNode, err)
+	}
+	if ctlErr != nil {
+		f.Close()
+		return fmt.Errorf("failed to set %s nonblocking: %v", devNode, ctlErr)
+	}
+
+	cancelPipe := [2]int{}
+	if err := unix.Pipe2(cancelPipe[:], unix.O_NONBLOCK); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to create cancel pipe for %s: %v", devNode, err)
+	}
+
+	session := &evdevSession{file: f, fd: fd, cancelPipe: cancelPipe, stop: make(chan struct{}), done: make(chan struct{})}
+
+	d.mu.Lock()
+	d.sessions[deviceName] = session
+	d.mu.Unlock()
+
+	go d.pollLoop(deviceName, session, resources)
+
+	return nil
+}
+
+// stopSession writes to a device's cancel pipe, which wakes the Ppoll loop
+// so it can exit deterministically, waits for it to finish, then closes
+// the pipe and the device node.
+func (d *EvdevDriver) stopSession(deviceName string) {
+	d.mu.Lock()
+	session, ok := d.sessions[deviceName]
+	if ok {
+		delete(d.sessions, deviceName)
+	}
+	d.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	close(session.stop)
+	unix.Write(session.cancelPipe[1], []byte{0})
+	<-session.done
+
+	unix.Close(session.cancelPipe[0])
+	unix.Close(session.cancelPipe[1])
+	session.file.Close()
+}
+
+// pollLoop drives non-blocking reads of input_event structs via Ppoll so
+// that Stop(force=true) can interrupt it deterministically, unlike a
+// blocking Read on the underlying *os.File.
+func (d *EvdevDriver) pollLoop(deviceName string, session *evdevSession, resources []models.DeviceResource) {
+	defer close(session.done)
+
+	fds := []unix.PollFd{
+		{Fd: int32(session.fd), Events: unix.POLLIN},
+		{Fd: int32(session.cancelPipe[0]), Events: unix.POLLIN},
+	}
+	buf := make([]byte, inputEventSize)
+
+	for {
+		n, err := unix.Ppoll(fds, nil, nil)
+		if err != nil {
+			if err == unix.EINTR {
+				continue
+			}
+			d.lc.Warn(fmt.Sprintf("EvdevDriver: ppoll error on %s: %v", deviceName, err))
+			return
+		}
+		if n == 0 {
+			continue
+		}
+
+		if fds[1].Revents&unix.POLLIN != 0 {
+			// cancel pipe fired; Stop() is waiting for us to exit.
+			return
+		}
+
+		if fds[0].Revents&unix.POLLIN == 0 {
+			continue
+		}
+
+		read, err := unix.Read(session.fd, buf)
+		if err != nil {
+			if err == unix.EAGAIN {
+				continue
+			}
+			d.lc.Warn(fmt.Sprintf("EvdevDriver: read error on %s: %v", deviceName, err))
+			return
+		}
+		if read != len(buf) {
+			continue
+		}
+
+		evt := decodeInputEvent(buf)
+		cv, err := translateEvent(evt, resources)
+		if err != nil || cv == nil {
+			continue
+		}
+
+		select {
+		case d.asyncCh <- &dsModels.AsyncValues{
+			DeviceName:    deviceName,
+			CommandValues: []*dsModels.CommandValue{cv},
+		}:
+		case <-session.stop:
+			return
+		}
+	}
+}
+
+func decodeInputEvent(buf []byte) inputEvent {
+	var evt inputEvent
+
+	// Sec/Usec occupy the leading half of the struct; their on-wire width
+	// is 8 bytes each on 64-bit Linux and 4 bytes each on 32-bit Linux.
+	half := (len(buf) - 8) / 2
+	if half == 8 {
+		evt.Sec = int64(binary.LittleEndian.Uint64(buf[0:8]))
+		evt.Usec = int64(binary.LittleEndian.Uint64(buf[8:16]))
+	} else {
+		evt.Sec = int64(int32(binary.LittleEndian.Uint32(buf[0:4])))
+		evt.Usec = int64(int32(binary.LittleEndian.Uint32(buf[4:8])))
+	}
+
+	evt.Type = binary.LittleEndian.Uint16(buf[len(buf)-8 : len(buf)-6])
+	evt.Code = binary.LittleEndian.Uint16(buf[len(buf)-6 : len(buf)-4])
+	evt.Value = int32(binary.LittleEndian.Uint32(buf[len(buf)-4:]))
+	return evt
+}
+
+// translateEvent maps a raw key/abs/rel event to the device resource
+// configured for its type/code via the DeviceResource attributes
+// "eventType" and "code", returning nil when no resource is mapped.
+func translateEvent(evt inputEvent, resources []models.DeviceResource) (*dsModels.CommandValue, error) {
+	for _, dr := range resources {
+		switch evt.Type {
+		case evKey:
+			if dr.Attributes["eventType"] != "EV_KEY" {
+				continue
+			}
+		case evAbs:
+			if dr.Attributes["eventType"] != "EV_ABS" {
+				continue
+			}
+		case evRel:
+			if dr.Attributes["eventType"] != "EV_REL" {
+				continue
+			}
+		default:
+			continue
+		}
+
+		if dr.Attributes["code"] != fmt.Sprintf("%d", evt.Code) {
+			continue
+		}
+
+		// struct input_event's timestamp is seconds + microseconds since
+		// the epoch; the SDK wants milliseconds.
+		timestamp := evt.Sec*1000 + evt.Usec/1000
+		ro := &dsModels.ResourceOperation{Object: dr.Name}
+		return dsModels.NewInt32Value(ro, timestamp, evt.Value)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,48 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+// This file lets the example compile on non-Linux platforms; evdev is a
+// Linux-only API, so EvdevDriver is not functional here.
+//
+package driver
+
+import (
+	"fmt"
+
+	dsModels "github.com/edgexfoundry/device-dcs/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/logging"
+	"github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+// EvdevDriver is a non-functional stub outside of Linux, where evdev is
+// unavailable.
+type EvdevDriver struct {
+	lc logger.LoggingClient
+}
+
+func (d *EvdevDriver) Initialize(lc logger.LoggingClient, asyncCh chan<- *dsModels.AsyncValues) error {
+	d.lc = lc
+	return nil
+}
+
+func (d *EvdevDriver) HandleReadCommands(deviceName string, protocols map[string]models.ProtocolProperties, reqs []dsModels.CommandRequest) (res []*dsModels.CommandValue, err error) {
+	return nil, fmt.Errorf("EvdevDriver: evdev is only supported on Linux")
+}
+
+func (d *EvdevDriver) HandleWriteCommands(deviceName string, protocols map[string]models.ProtocolProperties, reqs []dsModels.CommandRequest,
+	params []*dsModels.CommandValue) error {
+	return fmt.Errorf("EvdevDriver: evdev is only supported on Linux")
+}
+
+func (d *EvdevDriver) DisconnectDevice(deviceName string, protocols map[string]models.ProtocolProperties) error {
+	return nil
+}
+
+func (d *EvdevDriver) Stop(force bool) error {
+	return nil
+}
@@ -0,0 +1,88 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package driver
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+func encodeInputEvent(t *testing.T, sec, usec int64, typ, code uint16, value int32) []byte {
+	t.Helper()
+
+	buf := make([]byte, inputEventSize)
+	half := (len(buf) - 8) / 2
+	if half == 8 {
+		binary.LittleEndian.PutUint64(buf[0:8], uint64(sec))
+		binary.LittleEndian.PutUint64(buf[8:16], uint64(usec))
+	} else {
+		binary.LittleEndian.PutUint32(buf[0:4], uint32(sec))
+		binary.LittleEndian.PutUint32(buf[4:8], uint32(usec))
+	}
+	binary.LittleEndian.PutUint16(buf[len(buf)-8:len(buf)-6], typ)
+	binary.LittleEndian.PutUint16(buf[len(buf)-6:len(buf)-4], code)
+	binary.LittleEndian.PutUint32(buf[len(buf)-4:], uint32(value))
+	return buf
+}
+
+func TestDecodeInputEvent(t *testing.T) {
+	buf := encodeInputEvent(t, 100, 500000, evKey, 30, 1)
+
+	evt := decodeInputEvent(buf)
+
+	if evt.Sec != 100 || evt.Usec != 500000 || evt.Type != evKey || evt.Code != 30 || evt.Value != 1 {
+		t.Fatalf("decodeInputEvent() = %+v, want {Sec:100 Usec:500000 Type:%d Code:30 Value:1}", evt, evKey)
+	}
+}
+
+func TestTranslateEvent(t *testing.T) {
+	resources := []models.DeviceResource{
+		{
+			Name: "KeyA",
+			Attributes: map[string]string{
+				"eventType": "EV_KEY",
+				"code":      "30",
+			},
+		},
+	}
+
+	evt := decodeInputEvent(encodeInputEvent(t, 1, 2000, evKey, 30, 1))
+
+	cv, err := translateEvent(evt, resources)
+	if err != nil {
+		t.Fatalf("translateEvent() error = %v", err)
+	}
+	if cv == nil {
+		t.Fatal("translateEvent() = nil, want a CommandValue for the mapped resource")
+	}
+	if cv.RO.Object != "KeyA" {
+		t.Errorf("translateEvent() RO.Object = %q, want %q", cv.RO.Object, "KeyA")
+	}
+
+	v, err := cv.Int32Value()
+	if err != nil {
+		t.Fatalf("cv.Int32Value() error = %v", err)
+	}
+	if v != 1 {
+		t.Errorf("cv.Int32Value() = %d, want 1", v)
+	}
+
+	unmapped := []models.DeviceResource{
+		{Name: "Other", Attributes: map[string]string{"eventType": "EV_ABS", "code": "0"}},
+	}
+	cv, err = translateEvent(evt, unmapped)
+	if err != nil {
+		t.Fatalf("translateEvent() error = %v", err)
+	}
+	if cv != nil {
+		t.Errorf("translateEvent() = %+v, want nil for an unmapped resource", cv)
+	}
+}
@@ -0,0 +1,19 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+// GrpcOptions configures the optional DMI-style management gRPC server
+// started alongside the device service.
+type GrpcOptions struct {
+	Listen string
+
+	Secure     bool
+	CertFile   string
+	KeyFile    string
+	CAFile     string
+	SkipVerify bool
+}
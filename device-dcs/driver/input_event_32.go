@@ -0,0 +1,23 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && (386 || arm)
+
+package driver
+
+// inputEvent holds one decoded evdev reading. On 32-bit Linux the
+// kernel's struct input_event packs timeval's tv_sec/tv_usec as 32-bit
+// fields (wire size 16); Sec/Usec are still widened to int64 here for a
+// single arch-independent decodeInputEvent.
+type inputEvent struct {
+	Sec   int64
+	Usec  int64
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+const inputEventSize = 16
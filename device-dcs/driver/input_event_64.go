@@ -0,0 +1,21 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && (amd64 || arm64)
+
+package driver
+
+// inputEvent mirrors the kernel's struct input_event on 64-bit Linux,
+// where timeval's tv_sec/tv_usec are both 64-bit (size 24).
+type inputEvent struct {
+	Sec   int64
+	Usec  int64
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+const inputEventSize = 24
@@ -0,0 +1,432 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This package provides a Modbus TCP/RTU implementation of
+// a ProtocolDriver interface.
+//
+package driver
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	dsModels "github.com/edgexfoundry/device-dcs/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/logging"
+	"github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/goburrow/modbus"
+)
+
+// primary table selectors, matching the DeviceResource attribute
+// "primaryTable" values supported by this driver.
+const (
+	discretesInput   = "DISCRETES_INPUT"
+	coils            = "COILS"
+	inputRegisters   = "INPUT_REGISTERS"
+	holdingRegisters = "HOLDING_REGISTERS"
+)
+
+// connectionInfo identifies a unique Modbus endpoint. Devices that share
+// the same protocol tuple share the same pooled *modbus.Client.
+type connectionInfo struct {
+	// TCP
+	address string
+	port    string
+	unitID  string
+
+	// RTU
+	serialPort string
+	baudRate   string
+	dataBits   string
+	parity     string
+	stopBits   string
+	slaveID    string
+}
+
+// modbusConnection pairs a modbus.Client with its handler so the
+// underlying transport can be closed when no longer needed.
+type modbusConnection struct {
+	client  modbus.Client
+	handler interface {
+		Close() error
+	}
+}
+
+// ModbusDriver talks to real Modbus TCP/RTU PLCs. It implements the same
+// ProtocolDriver interface as SimpleDriver.
+type ModbusDriver struct {
+	lc      logger.LoggingClient
+	asyncCh chan<- *dsModels.AsyncValues
+
+	mu          sync.Mutex
+	connections map[connectionInfo]*modbusConnection
+}
+
+// Initialize performs protocol-specific initialization for the device
+// service.
+func (d *ModbusDriver) Initialize(lc logger.LoggingClient, asyncCh chan<- *dsModels.AsyncValues) error {
+	d.lc = lc
+	d.asyncCh = asyncCh
+	d.connections = make(map[connectionInfo]*modbusConnection)
+	return nil
+}
+
+// DisconnectDevice handles protocol-specific cleanup when a device
+// is removed.
+func (d *ModbusDriver) DisconnectDevice(deviceName string, protocols map[string]models.ProtocolProperties) error {
+	info, err := connectionInfoFrom(protocols)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	conn, ok := d.connections[info]
+	if !ok {
+		return nil
+	}
+	delete(d.connections, info)
+	return conn.handler.Close()
+}
+
+// HandleReadCommands triggers a protocol Read operation for the specified device.
+func (d *ModbusDriver) HandleReadCommands(deviceName string, protocols map[string]models.ProtocolProperties, reqs []dsModels.CommandRequest) (res []*dsModels.CommandValue, err error) {
+	client, err := d.clientFor(protocols)
+	if err != nil {
+		return nil, err
+	}
+
+	res = make([]*dsModels.CommandValue, len(reqs))
+	for i, req := range reqs {
+		cv, err := d.readResource(client, &reqs[i].RO, req.DeviceResource)
+		if err != nil {
+			return nil, fmt.Errorf("ModbusDriver.HandleReadCommands: %v", err)
+		}
+		res[i] = cv
+	}
+
+	return res, nil
+}
+
+// HandleWriteCommands passes a slice of CommandRequest struct each representing
+// a ResourceOperation for a specific device resource.
+func (d *ModbusDriver) HandleWriteCommands(deviceName string, protocols map[string]models.ProtocolProperties, reqs []dsModels.CommandRequest,
+	params []*dsModels.CommandValue) error {
+
+	client, err := d.clientFor(protocols)
+	if err != nil {
+		return err
+	}
+
+	for i, param := range params {
+		if err := d.writeResource(client, reqs[i].DeviceResource, param); err != nil {
+			return fmt.Errorf("ModbusDriver.HandleWriteCommands: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// Stop the protocol-specific DS code to shutdown gracefully, or
+// if the force parameter is 'true', immediately. The driver is responsible
+// for closing any in-use channels, including the channel used to send async
+// readings (if supported).
+func (d *ModbusDriver) Stop(force bool) error {
+	d.lc.Debug(fmt.Sprintf("ModbusDriver.Stop called: force=%v", force))
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for info, conn := range d.connections {
+		if err := conn.handler.Close(); err != nil {
+			d.lc.Warn(fmt.Sprintf("ModbusDriver.Stop: error closing connection %+v: %v", info, err))
+		}
+		delete(d.connections, info)
+	}
+
+	return nil
+}
+
+// clientFor returns the pooled modbus.Client for the device's protocol
+// properties, creating and connecting one on first use.
+func (d *ModbusDriver) clientFor(protocols map[string]models.ProtocolProperties) (modbus.Client, error) {
+	info, err := connectionInfoFrom(protocols)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if conn, ok := d.connections[info]; ok {
+		return conn.client, nil
+	}
+
+	conn, err := newModbusConnection(info)
+	if err != nil {
+		return nil, err
+	}
+	d.connections[info] = conn
+
+	return conn.client, nil
+}
+
+func newModbusConnection(info connectionInfo) (*modbusConnection, error) {
+	if info.address != "" {
+		port, err := strconv.Atoi(info.port)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Port %q: %v", info.port, err)
+		}
+		unitID, err := strconv.Atoi(info.unitID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid UnitID %q: %v", info.unitID, err)
+		}
+
+		handler := modbus.NewTCPClientHandler(fmt.Sprintf("%s:%d", info.address, port))
+		handler.SlaveId = byte(unitID)
+		if err := handler.Connect(); err != nil {
+			return nil, fmt.Errorf("failed to connect to %s:%d: %v", info.address, port, err)
+		}
+		return &modbusConnection{client: modbus.NewClient(handler), handler: handler}, nil
+	}
+
+	baudRate, err := strconv.Atoi(info.baudRate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BaudRate %q: %v", info.baudRate, err)
+	}
+	dataBits, err := strconv.Atoi(info.dataBits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DataBits %q: %v", info.dataBits, err)
+	}
+	stopBits, err := strconv.Atoi(info.stopBits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid StopBits %q: %v", info.stopBits, err)
+	}
+	slaveID, err := strconv.Atoi(info.slaveID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SlaveID %q: %v", info.slaveID, err)
+	}
+
+	handler := modbus.NewRTUClientHandler(info.serialPort)
+	handler.BaudRate = baudRate
+	handler.DataBits = dataBits
+	handler.Parity = info.parity
+	handler.StopBits = stopBits
+	handler.SlaveId = byte(slaveID)
+	if err := handler.Connect(); err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", info.serialPort, err)
+	}
+
+	return &modbusConnection{client: modbus.NewClient(handler), handler: handler}, nil
+}
+
+// connectionInfoFrom extracts the TCP or RTU protocol tuple from a
+// device's ProtocolProperties. The "modbus-tcp" entry is used when present,
+// otherwise the "modbus-rtu" entry.
+func connectionInfoFrom(protocols map[string]models.ProtocolProperties) (connectionInfo, error) {
+	props, ok := protocols["modbus-tcp"]
+	if ok {
+		return connectionInfo{
+			address: props["Address"],
+			port:    props["Port"],
+			unitID:  props["UnitID"],
+		}, nil
+	}
+
+	props, ok = protocols["modbus-rtu"]
+	if ok {
+		return connectionInfo{
+			serialPort: props["SerialPort"],
+			baudRate:   props["BaudRate"],
+			dataBits:   props["DataBits"],
+			parity:     props["Parity"],
+			stopBits:   props["StopBits"],
+			slaveID:    props["SlaveID"],
+		}, nil
+	}
+
+	return connectionInfo{}, fmt.Errorf("no modbus-tcp or modbus-rtu protocol properties found")
+}
+
+// readResource issues the read function code implied by primaryTable and
+// decodes the result according to dataType.
+func (d *ModbusDriver) readResource(client modbus.Client, ro *dsModels.ResourceOperation, dr models.DeviceResource) (*dsModels.CommandValue, error) {
+	primaryTable := dr.Attributes["primaryTable"]
+	startingAddress, err := attrUint16(dr, "startingAddress")
+	if err != nil {
+		return nil, err
+	}
+	quantity, err := attrUint16(dr, "quantity")
+	if err != nil {
+		return nil, err
+	}
+	dataType := dr.Attributes["dataType"]
+
+	var raw []byte
+	switch primaryTable {
+	case discretesInput:
+		raw, err = client.ReadDiscreteInputs(startingAddress, quantity)
+	case coils:
+		raw, err = client.ReadCoils(startingAddress, quantity)
+	case inputRegisters:
+		raw, err = client.ReadInputRegisters(startingAddress, quantity)
+	case holdingRegisters:
+		raw, err = client.ReadHoldingRegisters(startingAddress, quantity)
+	default:
+		return nil, fmt.Errorf("unsupported primaryTable %q", primaryTable)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	return decodeValue(ro, dataType, raw, now)
+}
+
+// writeResource issues the write function code implied by primaryTable,
+// packing multi-register values big-endian as required.
+func (d *ModbusDriver) writeResource(client modbus.Client, dr models.DeviceResource, param *dsModels.CommandValue) error {
+	primaryTable := dr.Attributes["primaryTable"]
+	startingAddress, err := attrUint16(dr, "startingAddress")
+	if err != nil {
+		return err
+	}
+
+	switch primaryTable {
+	case coils:
+		v, err := param.BoolValue()
+		if err != nil {
+			return err
+		}
+		value := uint16(0x0000)
+		if v {
+			value = 0xFF00
+		}
+		_, err = client.WriteSingleCoil(startingAddress, value)
+		return err
+	case holdingRegisters:
+		raw, err := encodeValue(dr.Attributes["dataType"], param)
+		if err != nil {
+			return err
+		}
+		if len(raw) == 2 {
+			_, err = client.WriteSingleRegister(startingAddress, binary.BigEndian.Uint16(raw))
+			return err
+		}
+		_, err = client.WriteMultipleRegisters(startingAddress, uint16(len(raw)/2), raw)
+		return err
+	default:
+		return fmt.Errorf("primaryTable %q is not writable", primaryTable)
+	}
+}
+
+func attrUint16(dr models.DeviceResource, name string) (uint16, error) {
+	v, err := strconv.ParseUint(dr.Attributes[name], 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %v", name, dr.Attributes[name], err)
+	}
+	return uint16(v), nil
+}
+
+// dataTypeWidth returns the number of bytes decodeValue expects raw to
+// hold for dataType, or false if dataType is unsupported.
+func dataTypeWidth(dataType string) (int, bool) {
+	switch dataType {
+	case "BOOL":
+		return 1, true
+	case "INT16", "UINT16":
+		return 2, true
+	case "INT32", "UINT32", "FLOAT32":
+		return 4, true
+	default:
+		return 0, false
+	}
+}
+
+// decodeValue converts the big-endian register/coil bytes returned by the
+// PLC into the CommandValue matching dataType. A profile that pairs a
+// primaryTable with a dataType wider than the bytes actually read (e.g.
+// COILS with INT32) would otherwise panic on an out-of-range slice index;
+// check raw's length against dataType's expected width up front and fail
+// the read instead.
+func decodeValue(ro *dsModels.ResourceOperation, dataType string, raw []byte, now int64) (*dsModels.CommandValue, error) {
+	width, ok := dataTypeWidth(dataType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported dataType %q", dataType)
+	}
+	if len(raw) < width {
+		return nil, fmt.Errorf("dataType %q needs %d bytes, got %d", dataType, width, len(raw))
+	}
+
+	switch dataType {
+	case "BOOL":
+		return dsModels.NewBoolValue(ro, now, raw[0] != 0)
+	case "INT16":
+		return dsModels.NewInt16Value(ro, now, int16(binary.BigEndian.Uint16(raw)))
+	case "UINT16":
+		return dsModels.NewUint16Value(ro, now, binary.BigEndian.Uint16(raw))
+	case "INT32":
+		return dsModels.NewInt32Value(ro, now, int32(binary.BigEndian.Uint32(raw)))
+	case "UINT32":
+		return dsModels.NewUint32Value(ro, now, binary.BigEndian.Uint32(raw))
+	case "FLOAT32":
+		return dsModels.NewFloat32Value(ro, now, math.Float32frombits(binary.BigEndian.Uint32(raw)))
+	default:
+		return nil, fmt.Errorf("unsupported dataType %q", dataType)
+	}
+}
+
+// encodeValue packs a CommandValue into big-endian register words.
+func encodeValue(dataType string, param *dsModels.CommandValue) ([]byte, error) {
+	switch dataType {
+	case "INT16":
+		v, err := param.Int16Value()
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, 2)
+		binary.BigEndian.PutUint16(buf, uint16(v))
+		return buf, nil
+	case "UINT16":
+		v, err := param.Uint16Value()
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, 2)
+		binary.BigEndian.PutUint16(buf, v)
+		return buf, nil
+	case "INT32":
+		v, err := param.Int32Value()
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, 4)
+		binary.BigEndian.PutUint32(buf, uint32(v))
+		return buf, nil
+	case "UINT32":
+		v, err := param.Uint32Value()
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, 4)
+		binary.BigEndian.PutUint32(buf, v)
+		return buf, nil
+	case "FLOAT32":
+		v, err := param.Float32Value()
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, 4)
+		binary.BigEndian.PutUint32(buf, math.Float32bits(v))
+		return buf, nil
+	default:
+		return nil, fmt.Errorf("unsupported dataType %q", dataType)
+	}
+}
@@ -0,0 +1,146 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	dsModels "github.com/edgexfoundry/device-dcs/pkg/models"
+)
+
+func TestDecodeValue(t *testing.T) {
+	ro := &dsModels.ResourceOperation{Object: "Resource"}
+
+	tests := []struct {
+		dataType string
+		raw      []byte
+		check    func(t *testing.T, cv *dsModels.CommandValue)
+	}{
+		{"BOOL", []byte{1}, func(t *testing.T, cv *dsModels.CommandValue) {
+			v, err := cv.BoolValue()
+			if err != nil {
+				t.Fatalf("BoolValue() error = %v", err)
+			}
+			if !v {
+				t.Errorf("BoolValue() = %v, want true", v)
+			}
+		}},
+		{"INT16", []byte{0x00, 0x2A}, func(t *testing.T, cv *dsModels.CommandValue) {
+			v, err := cv.Int16Value()
+			if err != nil {
+				t.Fatalf("Int16Value() error = %v", err)
+			}
+			if v != 42 {
+				t.Errorf("Int16Value() = %d, want 42", v)
+			}
+		}},
+		{"UINT16", []byte{0xFF, 0xFF}, func(t *testing.T, cv *dsModels.CommandValue) {
+			v, err := cv.Uint16Value()
+			if err != nil {
+				t.Fatalf("Uint16Value() error = %v", err)
+			}
+			if v != 0xFFFF {
+				t.Errorf("Uint16Value() = %d, want 0xFFFF", v)
+			}
+		}},
+		{"INT32", []byte{0x00, 0x00, 0x00, 0x2A}, func(t *testing.T, cv *dsModels.CommandValue) {
+			v, err := cv.Int32Value()
+			if err != nil {
+				t.Fatalf("Int32Value() error = %v", err)
+			}
+			if v != 42 {
+				t.Errorf("Int32Value() = %d, want 42", v)
+			}
+		}},
+		{"UINT32", []byte{0xFF, 0xFF, 0xFF, 0xFF}, func(t *testing.T, cv *dsModels.CommandValue) {
+			v, err := cv.Uint32Value()
+			if err != nil {
+				t.Fatalf("Uint32Value() error = %v", err)
+			}
+			if v != 0xFFFFFFFF {
+				t.Errorf("Uint32Value() = %d, want 0xFFFFFFFF", v)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		cv, err := decodeValue(ro, tt.dataType, tt.raw, 0)
+		if err != nil {
+			t.Errorf("decodeValue(%q) error = %v", tt.dataType, err)
+			continue
+		}
+		if cv == nil {
+			t.Errorf("decodeValue(%q) = nil", tt.dataType)
+			continue
+		}
+		tt.check(t, cv)
+	}
+
+	if _, err := decodeValue(ro, "UNKNOWN", []byte{0}, 0); err == nil {
+		t.Error("decodeValue(\"UNKNOWN\") error = nil, want an unsupported dataType error")
+	}
+}
+
+func TestDecodeValueRawTooShort(t *testing.T) {
+	ro := &dsModels.ResourceOperation{Object: "Resource"}
+
+	// COILS reads return ceil(quantity/8) bytes; pairing that with a
+	// wider dataType like INT32 must fail cleanly rather than panic on
+	// an out-of-range slice index.
+	if _, err := decodeValue(ro, "INT32", []byte{0x00}, 0); err == nil {
+		t.Error("decodeValue(\"INT32\", 1 byte) error = nil, want a short-buffer error")
+	}
+}
+
+func TestEncodeValueInt16RoundTrip(t *testing.T) {
+	ro := &dsModels.ResourceOperation{Object: "Resource"}
+	cv, err := dsModels.NewInt16Value(ro, 0, -7)
+	if err != nil {
+		t.Fatalf("NewInt16Value() error = %v", err)
+	}
+
+	raw, err := encodeValue("INT16", cv)
+	if err != nil {
+		t.Fatalf("encodeValue(\"INT16\") error = %v", err)
+	}
+	if len(raw) != 2 {
+		t.Fatalf("encodeValue(\"INT16\") len = %d, want 2", len(raw))
+	}
+	if got := int16(binary.BigEndian.Uint16(raw)); got != -7 {
+		t.Errorf("encodeValue(\"INT16\") = %d, want -7", got)
+	}
+}
+
+func TestEncodeValueFloat32RoundTrip(t *testing.T) {
+	ro := &dsModels.ResourceOperation{Object: "Resource"}
+	cv, err := dsModels.NewFloat32Value(ro, 0, 3.5)
+	if err != nil {
+		t.Fatalf("NewFloat32Value() error = %v", err)
+	}
+
+	raw, err := encodeValue("FLOAT32", cv)
+	if err != nil {
+		t.Fatalf("encodeValue(\"FLOAT32\") error = %v", err)
+	}
+	if got := math.Float32frombits(binary.BigEndian.Uint32(raw)); got != 3.5 {
+		t.Errorf("encodeValue(\"FLOAT32\") = %v, want 3.5", got)
+	}
+}
+
+func TestEncodeValueUnsupportedDataType(t *testing.T) {
+	ro := &dsModels.ResourceOperation{Object: "Resource"}
+	cv, err := dsModels.NewInt16Value(ro, 0, 1)
+	if err != nil {
+		t.Fatalf("NewInt16Value() error = %v", err)
+	}
+
+	if _, err := encodeValue("UNKNOWN", cv); err == nil {
+		t.Error("encodeValue(\"UNKNOWN\") error = nil, want an unsupported dataType error")
+	}
+}
@@ -15,13 +15,42 @@ import (
 	dsModels "github.com/edgexfoundry/device-dcs/pkg/models"
 	"github.com/edgexfoundry/go-mod-core-contracts/clients/logging"
 	"github.com/edgexfoundry/go-mod-core-contracts/models"
+	"sync"
 	"time"
 )
 
 type SimpleDriver struct {
-	lc           logger.LoggingClient
-	asyncCh      chan<- *dsModels.AsyncValues
-	randomDevices map[string]*randomDevice
+	lc      logger.LoggingClient
+	asyncCh chan<- *dsModels.AsyncValues
+
+	// mu guards randomDevices, deviceProtocols, and lastValues, which are
+	// read and written from HandleReadCommands/HandleWriteCommands, the
+	// autoEvents sampling goroutines, and the management server's
+	// Inventory/Metric calls.
+	mu              sync.Mutex
+	randomDevices   map[string]*randomDevice
+	deviceProtocols map[string]map[string]models.ProtocolProperties
+	lastValues      map[string]int64
+
+	discoverConfig DiscoverConfig
+	discoveryStats discoveryStats
+
+	autoEventConfigs []AutoEventConfig
+	autoEvents       autoEventManager
+}
+
+// randomDeviceFor returns the randomDevice backing deviceName, creating
+// one on first use.
+func (s *SimpleDriver) randomDeviceFor(deviceName string) *randomDevice {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rd, ok := s.randomDevices[deviceName]
+	if !ok {
+		rd = newRandomDevice()
+		s.randomDevices[deviceName] = rd
+	}
+	return rd
 }
 
 // DisconnectDevice handles protocol-specific cleanup when a device
@@ -36,27 +65,46 @@ func (s *SimpleDriver) Initialize(lc logger.LoggingClient, asyncCh chan<- *dsMod
 	s.lc = lc
 	s.asyncCh = asyncCh
 	s.randomDevices = make(map[string]*randomDevice)
+	s.deviceProtocols = make(map[string]map[string]models.ProtocolProperties)
+	s.lastValues = make(map[string]int64)
+
+	discoverCfg, autoEventCfgs, err := loadDriverConfig(lc, configFilePath())
+	if err != nil {
+		return fmt.Errorf("SimpleDriver.Initialize: %v", err)
+	}
+	s.discoverConfig = discoverCfg
+	s.autoEventConfigs = autoEventCfgs
+
+	s.autoEvents.init(s)
 	return nil
 }
 
 // HandleReadCommands triggers a protocol Read operation for the specified device.
 func (s *SimpleDriver) HandleReadCommands(deviceName string, protocols map[string]models.ProtocolProperties, reqs []dsModels.CommandRequest) (res []*dsModels.CommandValue, err error) {
 
-	rd, ok := s.randomDevices[deviceName]
-	if !ok {
-		rd = newRandomDevice()
-		s.randomDevices[deviceName] = rd
-	}
+	rd := s.randomDeviceFor(deviceName)
+
+	s.mu.Lock()
+	s.deviceProtocols[deviceName] = protocols
+	s.mu.Unlock()
+
+	s.autoEvents.Start(deviceName, s.autoEventConfigs)
 
 	res = make([]*dsModels.CommandValue, len(reqs))
 	now := time.Now().UnixNano() / int64(time.Millisecond)
 
 	for i, req := range reqs {
 		t := req.DeviceResource.Properties.Value.Type
+
+		s.mu.Lock()
 		v, err := rd.value(t)
 		if err != nil {
+			s.mu.Unlock()
 			return nil, err
 		}
+		s.lastValues[deviceName] = int64(v)
+		s.mu.Unlock()
+
 		var cv *dsModels.CommandValue
 		switch t {
 		case "Int8":
@@ -79,11 +127,11 @@ func (s *SimpleDriver) HandleReadCommands(deviceName string, protocols map[strin
 func (s *SimpleDriver) HandleWriteCommands(deviceName string, protocols map[string]models.ProtocolProperties, reqs []dsModels.CommandRequest,
 	params []*dsModels.CommandValue) error {
 
-	rd, ok := s.randomDevices[deviceName]
-	if !ok {
-		rd = newRandomDevice()
-		s.randomDevices[deviceName] = rd
-	}
+	rd := s.randomDeviceFor(deviceName)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deviceProtocols[deviceName] = protocols
 
 	for _, param := range params {
 		switch param.RO.Object {
@@ -161,5 +209,6 @@ func (s *SimpleDriver) HandleWriteCommands(deviceName string, protocols map[stri
 // readings (if supported).
 func (s *SimpleDriver) Stop(force bool) error {
 	s.lc.Debug(fmt.Sprintf("SimpleDriver.Stop called: force=%v", force))
+	s.autoEvents.stop(force)
 	return nil
 }
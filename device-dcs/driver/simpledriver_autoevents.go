@@ -0,0 +1,167 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This file adds an AutoEvent-style scheduled sampling subsystem to
+// SimpleDriver, turning the pull-only example into a template for
+// push-mode drivers. Configuration lives under the service's [AutoEvents]
+// TOML section.
+//
+package driver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	dsModels "github.com/edgexfoundry/device-dcs/pkg/models"
+)
+
+// AutoEventConfig mirrors one entry of the service's [[AutoEvents]]
+// configuration list.
+type AutoEventConfig struct {
+	Resource string
+	Interval time.Duration
+	OnChange bool
+}
+
+// DefaultAutoEventConfigs returns the configuration used when the
+// service's [[AutoEvents]] list is absent: push the Int32 resource every
+// 10 seconds, matching the only type sampleResource generates.
+func DefaultAutoEventConfigs() []AutoEventConfig {
+	return []AutoEventConfig{
+		{Resource: "Int32", Interval: 10 * time.Second, OnChange: false},
+	}
+}
+
+// autoEventManager owns the per-device sampling goroutines and the shared
+// context used to cancel them on Stop.
+type autoEventManager struct {
+	driver *SimpleDriver
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu           sync.Mutex
+	active       map[string]bool
+	lastReported map[string]int32
+}
+
+func (m *autoEventManager) init(d *SimpleDriver) {
+	m.driver = d
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.active = make(map[string]bool)
+	m.lastReported = make(map[string]int32)
+}
+
+// Start launches a sampling goroutine for deviceName, one per configured
+// AutoEventConfig entry, that periodically generates a value and pushes
+// it via asyncCh. It is a no-op if the device already has a running
+// sampler.
+func (m *autoEventManager) Start(deviceName string, events []AutoEventConfig) {
+	m.mu.Lock()
+	if m.active[deviceName] {
+		m.mu.Unlock()
+		return
+	}
+	m.active[deviceName] = true
+	m.mu.Unlock()
+
+	for _, cfg := range events {
+		m.wg.Add(1)
+		go m.sample(deviceName, cfg)
+	}
+}
+
+func (m *autoEventManager) sample(deviceName string, cfg AutoEventConfig) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			cv, err := m.driver.sampleResource(deviceName, cfg.Resource)
+			if err != nil {
+				m.driver.lc.Warn(fmt.Sprintf("SimpleDriver.autoEvent: failed to sample %s/%s: %v", deviceName, cfg.Resource, err))
+				continue
+			}
+
+			if cfg.OnChange && !m.changed(deviceName, cfg.Resource, cv) {
+				continue
+			}
+
+			select {
+			case m.driver.asyncCh <- &dsModels.AsyncValues{DeviceName: deviceName, CommandValues: []*dsModels.CommandValue{cv}}:
+			case <-m.ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// changed reports whether resource's sampled value differs from the last
+// one reported for deviceName/resource, recording it either way. It
+// backs OnChange filtering; a resource whose value can't be read as an
+// Int32 is always reported, since AutoEvents samples are Int32-only.
+func (m *autoEventManager) changed(deviceName, resource string, cv *dsModels.CommandValue) bool {
+	v, err := cv.Int32Value()
+	if err != nil {
+		return true
+	}
+
+	key := deviceName + "/" + resource
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	last, ok := m.lastReported[key]
+	m.lastReported[key] = v
+	return !ok || last != v
+}
+
+// stop cancels the shared context so every sampler goroutine observes
+// <-ctx.Done(). When force is false, it waits for every sampler goroutine
+// to return; a sample still waiting on asyncCh when the context is
+// cancelled may be dropped rather than delivered. When force is true, it
+// returns immediately.
+func (m *autoEventManager) stop(force bool) {
+	m.cancel()
+
+	if force {
+		return
+	}
+
+	m.wg.Wait()
+}
+
+// sampleResource generates a value for resource using the same
+// randomDevice backing HandleReadCommands uses, always as an Int32, and
+// records it as deviceName's last-sampled value.
+func (s *SimpleDriver) sampleResource(deviceName, resource string) (*dsModels.CommandValue, error) {
+	rd := s.randomDeviceFor(deviceName)
+
+	// The example has no device resource registry to look up resource's
+	// configured type outside of a read request, so AutoEvents samples
+	// default to Int32.
+	s.mu.Lock()
+	v, err := rd.value("Int32")
+	if err == nil {
+		s.lastValues[deviceName] = int64(v)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	ro := &dsModels.ResourceOperation{Object: resource}
+	return dsModels.NewInt32Value(ro, now, int32(v))
+}
@@ -0,0 +1,102 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This file exposes read/write accessors onto SimpleDriver's internal
+// state for the DMI-style gRPC management surface in internal/grpc,
+// keeping that package free of any dependency on randomDevice internals.
+//
+package driver
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+// DeviceInventory describes one managed device for HWManagementService's
+// GetPhysicalInventory RPC.
+type DeviceInventory struct {
+	Name               string
+	ProtocolProperties map[string]string
+	MinInt8            int64
+	MaxInt8            int64
+	MinInt16           int64
+	MaxInt16           int64
+	MinInt32           int64
+	MaxInt32           int64
+}
+
+// DeviceMetric describes one managed device's current bounds and
+// last-sampled value for HWManagementService's GetMetric RPC.
+type DeviceMetric struct {
+	MinInt8   int64
+	MaxInt8   int64
+	MinInt16  int64
+	MaxInt16  int64
+	MinInt32  int64
+	MaxInt32  int64
+	LastValue int64
+}
+
+// Inventory lists the devices SimpleDriver currently manages.
+func (s *SimpleDriver) Inventory() []DeviceInventory {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inventory := make([]DeviceInventory, 0, len(s.randomDevices))
+	for name, rd := range s.randomDevices {
+		inventory = append(inventory, DeviceInventory{
+			Name:               name,
+			ProtocolProperties: flattenProtocolProperties(s.deviceProtocols[name]),
+			MinInt8:            rd.minInt8,
+			MaxInt8:            rd.maxInt8,
+			MinInt16:           rd.minInt16,
+			MaxInt16:           rd.maxInt16,
+			MinInt32:           rd.minInt32,
+			MaxInt32:           rd.maxInt32,
+		})
+	}
+	return inventory
+}
+
+// Metric returns deviceName's current bounds and last-sampled value.
+func (s *SimpleDriver) Metric(deviceName string) (DeviceMetric, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rd, ok := s.randomDevices[deviceName]
+	if !ok {
+		return DeviceMetric{}, fmt.Errorf("no such device %s", deviceName)
+	}
+
+	return DeviceMetric{
+		MinInt8:   rd.minInt8,
+		MaxInt8:   rd.maxInt8,
+		MinInt16:  rd.minInt16,
+		MaxInt16:  rd.maxInt16,
+		MinInt32:  rd.minInt32,
+		MaxInt32:  rd.maxInt32,
+		LastValue: s.lastValues[deviceName],
+	}, nil
+}
+
+// flattenProtocolProperties merges a device's per-protocol property bags
+// into the single flat map HWManagementService's DeviceInventoryEntry
+// carries, namespacing each key by its protocol name to avoid collisions.
+func flattenProtocolProperties(protocols map[string]models.ProtocolProperties) map[string]string {
+	flat := make(map[string]string)
+	for protocolName, props := range protocols {
+		for k, v := range props {
+			flat[protocolName+"."+k] = v
+		}
+	}
+	return flat
+}
+
+// SetLoggingLevel adjusts the driver's logging level at runtime.
+func (s *SimpleDriver) SetLoggingLevel(level string) error {
+	return s.lc.SetLogLevel(level)
+}
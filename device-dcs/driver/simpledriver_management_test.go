@@ -0,0 +1,36 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+func TestFlattenProtocolProperties(t *testing.T) {
+	protocols := map[string]models.ProtocolProperties{
+		"modbus-tcp": {"Address": "10.0.0.1", "Port": "502"},
+		"other":      {"Address": "simple-device-00"},
+	}
+
+	flat := flattenProtocolProperties(protocols)
+
+	want := map[string]string{
+		"modbus-tcp.Address": "10.0.0.1",
+		"modbus-tcp.Port":    "502",
+		"other.Address":      "simple-device-00",
+	}
+	if len(flat) != len(want) {
+		t.Fatalf("flattenProtocolProperties() = %v, want %v", flat, want)
+	}
+	for k, v := range want {
+		if flat[k] != v {
+			t.Errorf("flattenProtocolProperties()[%q] = %q, want %q", k, flat[k], v)
+		}
+	}
+}
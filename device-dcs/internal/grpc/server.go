@@ -0,0 +1,155 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This package stands up the optional DMI-style management gRPC server,
+// exposing HWManagementService against the running SimpleDriver's state.
+//
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/edgexfoundry/device-dcs/driver"
+	"github.com/edgexfoundry/device-dcs/internal/handler"
+	dmiv1 "github.com/edgexfoundry/device-dcs/pkg/dmi/v1"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// hwManagementServer adapts SimpleDriver to the generated
+// HWManagementServiceServer interface.
+type hwManagementServer struct {
+	dmiv1.UnimplementedHWManagementServiceServer
+
+	lc logger.LoggingClient
+	sd *driver.SimpleDriver
+}
+
+func (h *hwManagementServer) GetPhysicalInventory(ctx context.Context, req *dmiv1.GetPhysicalInventoryRequest) (*dmiv1.GetPhysicalInventoryResponse, error) {
+	inventory := h.sd.Inventory()
+	resp := &dmiv1.GetPhysicalInventoryResponse{Devices: make([]*dmiv1.DeviceInventoryEntry, 0, len(inventory))}
+	for _, d := range inventory {
+		resp.Devices = append(resp.Devices, &dmiv1.DeviceInventoryEntry{
+			Name:               d.Name,
+			ProtocolProperties: d.ProtocolProperties,
+			MinInt8:            d.MinInt8,
+			MaxInt8:            d.MaxInt8,
+			MinInt16:           d.MinInt16,
+			MaxInt16:           d.MaxInt16,
+			MinInt32:           d.MinInt32,
+			MaxInt32:           d.MaxInt32,
+		})
+	}
+	return resp, nil
+}
+
+func (h *hwManagementServer) GetMetric(ctx context.Context, req *dmiv1.GetMetricRequest) (*dmiv1.GetMetricResponse, error) {
+	m, err := h.sd.Metric(req.DeviceName)
+	if err != nil {
+		return nil, err
+	}
+	return &dmiv1.GetMetricResponse{
+		MinInt8:   m.MinInt8,
+		MaxInt8:   m.MaxInt8,
+		MinInt16:  m.MinInt16,
+		MaxInt16:  m.MaxInt16,
+		MinInt32:  m.MinInt32,
+		MaxInt32:  m.MaxInt32,
+		LastValue: m.LastValue,
+	}, nil
+}
+
+func (h *hwManagementServer) SetLoggingLevel(ctx context.Context, req *dmiv1.SetLoggingLevelRequest) (*dmiv1.SetLoggingLevelResponse, error) {
+	if err := h.sd.SetLoggingLevel(req.Level); err != nil {
+		return nil, err
+	}
+	h.lc.Info(fmt.Sprintf("HWManagementService.SetLoggingLevel: logging level set to %s", req.Level))
+	return &dmiv1.SetLoggingLevelResponse{Success: true}, nil
+}
+
+func (h *hwManagementServer) GetDiscoveryStatus(ctx context.Context, req *dmiv1.GetDiscoveryStatusRequest) (*dmiv1.GetDiscoveryStatusResponse, error) {
+	lastRun, lastCount := handler.DiscoveryStatusHandler(h.sd)
+
+	var lastRunUnixMillis int64
+	if !lastRun.IsZero() {
+		lastRunUnixMillis = lastRun.UnixNano() / int64(time.Millisecond)
+	}
+
+	return &dmiv1.GetDiscoveryStatusResponse{
+		LastRunUnixMillis: lastRunUnixMillis,
+		LastCount:         int32(lastCount),
+	}, nil
+}
+
+// Serve starts the HWManagementService gRPC server on opts.Listen and
+// blocks until the listener errors or the server is stopped. Callers
+// typically invoke it from its own goroutine.
+func Serve(lc logger.LoggingClient, sd *driver.SimpleDriver, opts driver.GrpcOptions) error {
+	lis, err := net.Listen("tcp", opts.Listen)
+	if err != nil {
+		return fmt.Errorf("grpc: failed to listen on %s: %v", opts.Listen, err)
+	}
+
+	creds, err := transportCredentials(opts)
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer(grpc.Creds(creds), grpc.ForceServerCodec(jsonCodec{}))
+	dmiv1.RegisterHWManagementServiceServer(server, &hwManagementServer{lc: lc, sd: sd})
+
+	lc.Info(fmt.Sprintf("grpc: HWManagementService listening on %s (secure=%v)", opts.Listen, opts.Secure))
+	return server.Serve(lis)
+}
+
+// transportCredentials builds the server-side TLS credentials for
+// opts. Go's TLS stack only consults InsecureSkipVerify on the client
+// side (it controls whether a client verifies the server's
+// certificate), so it has no server-side equivalent; opts.SkipVerify
+// instead relaxes mutual TLS by requesting a client certificate
+// without verifying it against opts.CAFile, rather than setting a
+// field the server side would silently ignore.
+func transportCredentials(opts driver.GrpcOptions) (credentials.TransportCredentials, error) {
+	if !opts.Secure {
+		return insecure.NewCredentials(), nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("grpc: failed to load TLS certificate: %v", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if opts.CAFile != "" {
+		caPEM, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("grpc: failed to read CAFile: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("grpc: failed to parse CAFile %s", opts.CAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		if opts.SkipVerify {
+			tlsConfig.ClientAuth = tls.RequireAnyClientCert
+		} else {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
@@ -0,0 +1,19 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"time"
+
+	"github.com/edgexfoundry/device-dcs/driver"
+)
+
+// DiscoveryStatusHandler reports when sd's Discover() last ran and how
+// many candidate devices it produced.
+func DiscoveryStatusHandler(sd *driver.SimpleDriver) (lastRun time.Time, lastCount int) {
+	return sd.DiscoveryStatus()
+}
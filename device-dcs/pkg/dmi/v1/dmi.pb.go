@@ -0,0 +1,62 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This file is the hand-maintained Go mirror of dmi.proto. This tree has
+// no protoc/protoc-gen-go available to regenerate it, so the message
+// types below are plain structs rather than protoreflect-backed
+// messages and cannot satisfy grpc-go's default "proto" codec.
+// internal/grpc pairs them with a JSON wire codec instead (see
+// internal/grpc/codec.go). Keep the field names and json tags in sync
+// with dmi.proto by hand until protoc is available to regenerate this
+// file for real.
+package dmiv1
+
+type GetPhysicalInventoryRequest struct{}
+
+type DeviceInventoryEntry struct {
+	Name               string            `json:"name"`
+	ProtocolProperties map[string]string `json:"protocol_properties"`
+	MinInt8            int64             `json:"min_int8"`
+	MaxInt8            int64             `json:"max_int8"`
+	MinInt16           int64             `json:"min_int16"`
+	MaxInt16           int64             `json:"max_int16"`
+	MinInt32           int64             `json:"min_int32"`
+	MaxInt32           int64             `json:"max_int32"`
+}
+
+type GetPhysicalInventoryResponse struct {
+	Devices []*DeviceInventoryEntry `json:"devices"`
+}
+
+type GetMetricRequest struct {
+	DeviceName string `json:"device_name"`
+}
+
+type GetMetricResponse struct {
+	MinInt8   int64 `json:"min_int8"`
+	MaxInt8   int64 `json:"max_int8"`
+	MinInt16  int64 `json:"min_int16"`
+	MaxInt16  int64 `json:"max_int16"`
+	MinInt32  int64 `json:"min_int32"`
+	MaxInt32  int64 `json:"max_int32"`
+	LastValue int64 `json:"last_value"`
+}
+
+type SetLoggingLevelRequest struct {
+	Level string `json:"level"`
+}
+
+type SetLoggingLevelResponse struct {
+	Success bool `json:"success"`
+}
+
+type GetDiscoveryStatusRequest struct{}
+
+type GetDiscoveryStatusResponse struct {
+	// LastRunUnixMillis is 0 if Discover() has never run.
+	LastRunUnixMillis int64 `json:"last_run_unix_millis"`
+	LastCount         int32 `json:"last_count"`
+}
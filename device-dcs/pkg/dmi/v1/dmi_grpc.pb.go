@@ -0,0 +1,181 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// This file is the hand-maintained Go mirror of the HWManagementService
+// client/server stubs protoc-gen-go-grpc would otherwise generate from
+// dmi.proto (see dmi.pb.go for why). It is wire-compatible with the
+// generated shape, but the server must be started with the JSON codec
+// from internal/grpc/codec.go rather than relying on the default
+// "proto" codec, since the message types in dmi.pb.go aren't real
+// protoreflect messages.
+// source: dmi.proto
+
+package dmiv1
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// HWManagementServiceClient is the client API for HWManagementService.
+type HWManagementServiceClient interface {
+	GetPhysicalInventory(ctx context.Context, in *GetPhysicalInventoryRequest, opts ...grpc.CallOption) (*GetPhysicalInventoryResponse, error)
+	GetMetric(ctx context.Context, in *GetMetricRequest, opts ...grpc.CallOption) (*GetMetricResponse, error)
+	SetLoggingLevel(ctx context.Context, in *SetLoggingLevelRequest, opts ...grpc.CallOption) (*SetLoggingLevelResponse, error)
+	GetDiscoveryStatus(ctx context.Context, in *GetDiscoveryStatusRequest, opts ...grpc.CallOption) (*GetDiscoveryStatusResponse, error)
+}
+
+type hWManagementServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHWManagementServiceClient(cc grpc.ClientConnInterface) HWManagementServiceClient {
+	return &hWManagementServiceClient{cc}
+}
+
+func (c *hWManagementServiceClient) GetPhysicalInventory(ctx context.Context, in *GetPhysicalInventoryRequest, opts ...grpc.CallOption) (*GetPhysicalInventoryResponse, error) {
+	out := new(GetPhysicalInventoryResponse)
+	err := c.cc.Invoke(ctx, "/dmi.v1.HWManagementService/GetPhysicalInventory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hWManagementServiceClient) GetMetric(ctx context.Context, in *GetMetricRequest, opts ...grpc.CallOption) (*GetMetricResponse, error) {
+	out := new(GetMetricResponse)
+	err := c.cc.Invoke(ctx, "/dmi.v1.HWManagementService/GetMetric", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hWManagementServiceClient) SetLoggingLevel(ctx context.Context, in *SetLoggingLevelRequest, opts ...grpc.CallOption) (*SetLoggingLevelResponse, error) {
+	out := new(SetLoggingLevelResponse)
+	err := c.cc.Invoke(ctx, "/dmi.v1.HWManagementService/SetLoggingLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hWManagementServiceClient) GetDiscoveryStatus(ctx context.Context, in *GetDiscoveryStatusRequest, opts ...grpc.CallOption) (*GetDiscoveryStatusResponse, error) {
+	out := new(GetDiscoveryStatusResponse)
+	err := c.cc.Invoke(ctx, "/dmi.v1.HWManagementService/GetDiscoveryStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HWManagementServiceServer is the server API for HWManagementService.
+type HWManagementServiceServer interface {
+	GetPhysicalInventory(context.Context, *GetPhysicalInventoryRequest) (*GetPhysicalInventoryResponse, error)
+	GetMetric(context.Context, *GetMetricRequest) (*GetMetricResponse, error)
+	SetLoggingLevel(context.Context, *SetLoggingLevelRequest) (*SetLoggingLevelResponse, error)
+	GetDiscoveryStatus(context.Context, *GetDiscoveryStatusRequest) (*GetDiscoveryStatusResponse, error)
+}
+
+// UnimplementedHWManagementServiceServer must be embedded for forward
+// compatibility with added methods.
+type UnimplementedHWManagementServiceServer struct{}
+
+func (UnimplementedHWManagementServiceServer) GetPhysicalInventory(context.Context, *GetPhysicalInventoryRequest) (*GetPhysicalInventoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPhysicalInventory not implemented")
+}
+
+func (UnimplementedHWManagementServiceServer) GetMetric(context.Context, *GetMetricRequest) (*GetMetricResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetric not implemented")
+}
+
+func (UnimplementedHWManagementServiceServer) SetLoggingLevel(context.Context, *SetLoggingLevelRequest) (*SetLoggingLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLoggingLevel not implemented")
+}
+
+func (UnimplementedHWManagementServiceServer) GetDiscoveryStatus(context.Context, *GetDiscoveryStatusRequest) (*GetDiscoveryStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDiscoveryStatus not implemented")
+}
+
+func RegisterHWManagementServiceServer(s *grpc.Server, srv HWManagementServiceServer) {
+	s.RegisterService(&hWManagementServiceServiceDesc, srv)
+}
+
+func _HWManagementService_GetPhysicalInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPhysicalInventoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HWManagementServiceServer).GetPhysicalInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/dmi.v1.HWManagementService/GetPhysicalInventory"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HWManagementServiceServer).GetPhysicalInventory(ctx, req.(*GetPhysicalInventoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HWManagementService_GetMetric_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetricRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HWManagementServiceServer).GetMetric(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/dmi.v1.HWManagementService/GetMetric"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HWManagementServiceServer).GetMetric(ctx, req.(*GetMetricRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HWManagementService_SetLoggingLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLoggingLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HWManagementServiceServer).SetLoggingLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/dmi.v1.HWManagementService/SetLoggingLevel"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HWManagementServiceServer).SetLoggingLevel(ctx, req.(*SetLoggingLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HWManagementService_GetDiscoveryStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDiscoveryStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HWManagementServiceServer).GetDiscoveryStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/dmi.v1.HWManagementService/GetDiscoveryStatus"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HWManagementServiceServer).GetDiscoveryStatus(ctx, req.(*GetDiscoveryStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var hWManagementServiceServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dmi.v1.HWManagementService",
+	HandlerType: (*HWManagementServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "GetPhysicalInventory", Handler: _HWManagementService_GetPhysicalInventory_Handler},
+		{MethodName: "GetMetric", Handler: _HWManagementService_GetMetric_Handler},
+		{MethodName: "SetLoggingLevel", Handler: _HWManagementService_SetLoggingLevel_Handler},
+		{MethodName: "GetDiscoveryStatus", Handler: _HWManagementService_GetDiscoveryStatus_Handler},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "dmi.proto",
+}